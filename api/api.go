@@ -0,0 +1,75 @@
+// Package api holds the small pieces shared by every resource controller:
+// the error type that maps to an HTTP status, the adapter that turns a
+// fallible handler into an http.HandlerFunc, and the body-size middleware
+// applied to every route.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+const (
+	// DefaultBodySize caps ordinary JSON request bodies.
+	DefaultBodySize = 1 << 20 // 1MiB
+
+	// MaxUploadSize caps the turn zip upload route, which needs far more
+	// headroom than DefaultBodySize.
+	MaxUploadSize = 64 << 20 // 64MiB
+)
+
+// HTTPError is an error that carries the HTTP status it should be reported
+// with, so handlers can return a plain error for anything unexpected (which
+// HandlerFunc reports as a 500) and an *HTTPError for anything the caller
+// did wrong.
+type HTTPError struct {
+	Status int
+	Err    error
+}
+
+func (e *HTTPError) Error() string { return e.Err.Error() }
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+func NewBadRequestError(err error) error   { return &HTTPError{Status: http.StatusBadRequest, Err: err} }
+func NewNotFoundError(err error) error     { return &HTTPError{Status: http.StatusNotFound, Err: err} }
+func NewUnauthorizedError(err error) error { return &HTTPError{Status: http.StatusUnauthorized, Err: err} }
+func NewForbiddenError(err error) error    { return &HTTPError{Status: http.StatusForbidden, Err: err} }
+
+// HandlerFunc adapts a handler that can fail into a standard
+// http.HandlerFunc. A returned *HTTPError is reported with its own status;
+// any other error is logged and reported as a 500, so internal details
+// never leak to the client.
+func HandlerFunc(fn func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err == nil {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.Status
+		} else {
+			log.Print(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	}
+}
+
+// WithMaximumBodySize rejects request bodies larger than n bytes, so a
+// malformed or malicious client cannot exhaust memory/disk with an
+// oversized payload.
+func WithMaximumBodySize(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
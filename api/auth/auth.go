@@ -0,0 +1,135 @@
+// Package auth provides chi middleware for verifying bearer JWTs and
+// gating routes by role, so individual controllers do not need to know
+// anything about tokens.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Claims is the subset of a validated token the rest of the application
+// cares about.
+type Claims struct {
+	Subject string
+	Roles   []string
+}
+
+// HasRole reports whether c includes role.
+func (c Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenValidator verifies a raw bearer token and returns the Claims it
+// carries. Integrators that need something other than local JWT
+// verification (e.g. OAuth2 introspection) can implement this interface
+// and pass it to Middleware instead of NewJWTValidator.
+type TokenValidator interface {
+	Validate(ctx context.Context, rawToken string) (Claims, error)
+}
+
+var ErrMissingToken = errors.New("auth: missing bearer token")
+
+type claimsKey struct{}
+
+// Middleware extracts the bearer token from the Authorization header,
+// validates it with v, and stores the resulting Claims in the request
+// context for downstream handlers and RequireRole to read.
+func Middleware(v TokenValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := bearerToken(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := v.Validate(r.Context(), token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", ErrMissingToken
+	}
+	return strings.TrimPrefix(header, prefix), nil
+}
+
+// FromContext returns the Claims stored by Middleware, if any.
+func FromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// RequireRole gates a route behind a set of allowed roles. Middleware must
+// run earlier in the chain so that Claims are already in context.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := FromContext(r.Context())
+			if !ok {
+				http.Error(w, "missing claims", http.StatusUnauthorized)
+				return
+			}
+
+			for _, role := range roles {
+				if claims.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "forbidden", http.StatusForbidden)
+		})
+	}
+}
+
+// RequireSelfOrRole allows the request through when either the URL
+// parameter named param equals the caller's Subject, or the caller has one
+// of roles. It is used for routes like GET /games/byplayer/{accountId},
+// where a player may read their own data but only an admin may read
+// someone else's.
+func RequireSelfOrRole(param string, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := FromContext(r.Context())
+			if !ok {
+				http.Error(w, "missing claims", http.StatusUnauthorized)
+				return
+			}
+
+			if chi.URLParam(r, param) == claims.Subject {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, role := range roles {
+				if claims.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "forbidden", http.StatusForbidden)
+		})
+	}
+}
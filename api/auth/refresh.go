@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Controller exposes the token-refresh endpoint. It only works in
+// shared-secret mode: JWKS-backed deployments are expected to refresh
+// tokens against their own identity provider instead.
+type Controller struct {
+	secret    []byte
+	validity  time.Duration
+	clockSkew time.Duration
+}
+
+// NewController returns a Controller that re-signs tokens with secret,
+// valid for validity from the moment of refresh. A token may only be
+// refreshed up to clockSkew after it expired; beyond that it is treated
+// as unrecoverable and the caller must re-authenticate.
+func NewController(secret string, validity, clockSkew time.Duration) *Controller {
+	if validity == 0 {
+		validity = time.Hour
+	}
+	return &Controller{secret: []byte(secret), validity: validity, clockSkew: clockSkew}
+}
+
+type refreshRequest struct {
+	Token string `json:"token"`
+}
+
+type refreshResponse struct {
+	Token string `json:"token"`
+}
+
+// Refresh accepts an existing (possibly expired within the configured
+// clock skew) token and issues a new one with the same subject and roles.
+func (c *Controller) Refresh(w http.ResponseWriter, r *http.Request) error {
+	if len(c.secret) == 0 {
+		return errors.New("auth: refresh is only available in shared-secret mode")
+	}
+
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+
+	// Claims validation is disabled here because jwt.Parse rejects expired
+	// tokens outright and we want to allow refreshing one that expired only
+	// recently. exp is instead checked manually below, bounded by
+	// clockSkew, so a token can never be refreshed indefinitely.
+	parsed, err := jwt.Parse(req.Token, func(t *jwt.Token) (interface{}, error) {
+		return c.secret, nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return err
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return errors.New("auth: unexpected claims type")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return errors.New("auth: token has no expiry")
+	}
+
+	now := time.Now()
+	if now.After(expiresAt.Add(c.clockSkew)) {
+		return fmt.Errorf("auth: token expired too long ago to refresh (expired at %s)", expiresAt)
+	}
+
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(c.validity).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(c.secret)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(refreshResponse{Token: signed})
+}
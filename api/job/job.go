@@ -0,0 +1,87 @@
+// Package job exposes the HTTP surface around the job queue: polling a
+// job's status and the callback runner processes use to report results.
+package job
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alarmfox/game-repository/api"
+	queue "github.com/alarmfox/game-repository/job"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// Controller wires the job queue to chi handlers.
+type Controller struct {
+	queue       *queue.Queue
+	resultToken string
+}
+
+// NewController returns a Controller. resultToken is the shared secret a
+// runner must present on SubmitResult; it is not part of the user-facing
+// auth scheme since runners are trusted internal processes.
+func NewController(q *queue.Queue, resultToken string) *Controller {
+	return &Controller{queue: q, resultToken: resultToken}
+}
+
+// FindByID returns the current state of a job, letting a client that
+// uploaded a turn poll for its grading result.
+func (c *Controller) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	j, err := c.queue.Get(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(j)
+}
+
+type resultRequest struct {
+	Success bool   `json:"success"`
+	Result  string `json:"result"`
+}
+
+// SubmitResult is called by a runner once it finishes executing a job.
+func (c *Controller) SubmitResult(w http.ResponseWriter, r *http.Request) error {
+	// An empty resultToken must never authenticate: ConstantTimeCompare
+	// reports two empty byte slices as equal, so without this check an
+	// unconfigured token would let any caller (even one sending no header
+	// at all) forge a result for any job.
+	if c.resultToken == "" ||
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Runner-Token")), []byte(c.resultToken)) != 1 {
+		return api.NewUnauthorizedError(errors.New("invalid runner token"))
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	var req resultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	if req.Success {
+		err = c.queue.Complete(r.Context(), id, req.Result)
+	} else {
+		err = c.queue.Fail(r.Context(), id, req.Result)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
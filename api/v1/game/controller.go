@@ -0,0 +1,128 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alarmfox/game-repository/api"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// Controller wires the game repository to chi handlers.
+type Controller struct {
+	repo *Repository
+}
+
+// NewController returns a Controller backed by repo.
+func NewController(repo *Repository) *Controller {
+	return &Controller{repo: repo}
+}
+
+func (c *Controller) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	game, err := c.repo.FindByID(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&game))
+}
+
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) error {
+	games, err := c.repo.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]Game, 0, len(games))
+	for i := range games {
+		out = append(out, fromModel(&games[i]))
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+// FindByPlayer lists every game the player named by the "accountId" URL
+// parameter is enrolled in.
+func (c *Controller) FindByPlayer(w http.ResponseWriter, r *http.Request) error {
+	accountId := chi.URLParam(r, "accountId")
+
+	games, err := c.repo.FindByPlayer(r.Context(), accountId)
+	if err != nil {
+		return err
+	}
+
+	out := make([]Game, 0, len(games))
+	for i := range games {
+		out = append(out, fromModel(&games[i]))
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) error {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	game, err := c.repo.Create(r.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(fromModel(&game))
+}
+
+func (c *Controller) Update(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	game, err := c.repo.Update(r.Context(), id, req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&game))
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	if err := c.repo.Delete(r.Context(), id); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
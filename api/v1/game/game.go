@@ -0,0 +1,39 @@
+package game
+
+import (
+	"time"
+
+	"github.com/alarmfox/game-repository/model"
+)
+
+type Game struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CreateRequest struct {
+	Name string `json:"name"`
+}
+
+func (CreateRequest) Validate() error {
+	return nil
+}
+
+type UpdateRequest struct {
+	Name string `json:"name"`
+}
+
+func (UpdateRequest) Validate() error {
+	return nil
+}
+
+func fromModel(g *model.Game) Game {
+	return Game{
+		ID:        g.ID,
+		Name:      g.Name,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package game
+
+import (
+	"context"
+
+	"github.com/alarmfox/game-repository/model"
+	"gorm.io/gorm"
+)
+
+// Repository persists games in Postgres.
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository returns a Repository backed by db.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (model.Game, error) {
+	var game model.Game
+	err := r.db.WithContext(ctx).First(&game, id).Error
+	return game, err
+}
+
+func (r *Repository) List(ctx context.Context) ([]model.Game, error) {
+	var games []model.Game
+	err := r.db.WithContext(ctx).Find(&games).Error
+	return games, err
+}
+
+// FindByPlayer returns every game the player identified by accountId is
+// enrolled in.
+func (r *Repository) FindByPlayer(ctx context.Context, accountId string) ([]model.Game, error) {
+	var games []model.Game
+	err := r.db.WithContext(ctx).
+		Joins("JOIN player_games ON player_games.game_id = games.id").
+		Joins("JOIN players ON players.id = player_games.player_id").
+		Where("players.account_id = ?", accountId).
+		Find(&games).Error
+	return games, err
+}
+
+func (r *Repository) Create(ctx context.Context, req CreateRequest) (model.Game, error) {
+	game := model.Game{Name: req.Name}
+	err := r.db.WithContext(ctx).Create(&game).Error
+	return game, err
+}
+
+func (r *Repository) Update(ctx context.Context, id int64, req UpdateRequest) (model.Game, error) {
+	game, err := r.FindByID(ctx, id)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	game.Name = req.Name
+
+	err = r.db.WithContext(ctx).Save(&game).Error
+	return game, err
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.Game{}, id).Error
+}
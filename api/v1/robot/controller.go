@@ -0,0 +1,87 @@
+package robot
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/alarmfox/game-repository/api"
+)
+
+// Controller wires the robot storage to chi handlers.
+type Controller struct {
+	storage *RobotStorage
+}
+
+// NewController returns a Controller backed by storage.
+func NewController(storage *RobotStorage) *Controller {
+	return &Controller{storage: storage}
+}
+
+func parseFilter(r *http.Request) (Filter, error) {
+	var f Filter
+	if raw := r.URL.Query().Get("gameId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.GameId = id
+	}
+	return f, nil
+}
+
+func (c *Controller) FindByFilter(w http.ResponseWriter, r *http.Request) error {
+	f, err := parseFilter(r)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	robots, err := c.storage.FindByFilter(r.Context(), f)
+	if err != nil {
+		return err
+	}
+
+	out := make([]Robot, 0, len(robots))
+	for i := range robots {
+		out = append(out, fromModel(&robots[i]))
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+func (c *Controller) CreateBulk(w http.ResponseWriter, r *http.Request) error {
+	var req CreateBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	robots, err := c.storage.CreateBulk(r.Context(), req.Robots)
+	if err != nil {
+		return err
+	}
+
+	out := make([]Robot, 0, len(robots))
+	for i := range robots {
+		out = append(out, fromModel(&robots[i]))
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(out)
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) error {
+	f, err := parseFilter(r)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	if err := c.storage.Delete(r.Context(), f); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
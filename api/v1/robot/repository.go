@@ -0,0 +1,58 @@
+package robot
+
+import (
+	"context"
+
+	"github.com/alarmfox/game-repository/model"
+	"gorm.io/gorm"
+)
+
+// Filter narrows FindByFilter and Delete to a subset of robots.
+type Filter struct {
+	GameId int64
+}
+
+// RobotStorage persists robots in Postgres. Unlike the other resources,
+// robots are only ever read and written in bulk per game, so it exposes a
+// filter-based API rather than per-id CRUD.
+type RobotStorage struct {
+	db *gorm.DB
+}
+
+// NewRobotStorage returns a RobotStorage backed by db.
+func NewRobotStorage(db *gorm.DB) *RobotStorage {
+	return &RobotStorage{db: db}
+}
+
+func (s *RobotStorage) FindByFilter(ctx context.Context, f Filter) ([]model.Robot, error) {
+	q := s.db.WithContext(ctx)
+	if f.GameId != 0 {
+		q = q.Where("game_id = ?", f.GameId)
+	}
+
+	var robots []model.Robot
+	err := q.Find(&robots).Error
+	return robots, err
+}
+
+func (s *RobotStorage) CreateBulk(ctx context.Context, reqs []CreateRequest) ([]model.Robot, error) {
+	robots := make([]model.Robot, 0, len(reqs))
+	for _, req := range reqs {
+		robots = append(robots, model.Robot{
+			GameId:    req.GameId,
+			AccountId: req.AccountId,
+			Name:      req.Name,
+		})
+	}
+
+	err := s.db.WithContext(ctx).Create(&robots).Error
+	return robots, err
+}
+
+func (s *RobotStorage) Delete(ctx context.Context, f Filter) error {
+	q := s.db.WithContext(ctx)
+	if f.GameId != 0 {
+		q = q.Where("game_id = ?", f.GameId)
+	}
+	return q.Delete(&model.Robot{}).Error
+}
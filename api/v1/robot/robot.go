@@ -0,0 +1,45 @@
+package robot
+
+import (
+	"time"
+
+	"github.com/alarmfox/game-repository/model"
+)
+
+type Robot struct {
+	ID        int64     `json:"id"`
+	GameId    int64     `json:"gameId"`
+	AccountId string    `json:"accountId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateRequest struct {
+	GameId    int64  `json:"gameId"`
+	AccountId string `json:"accountId"`
+	Name      string `json:"name"`
+}
+
+func (CreateRequest) Validate() error {
+	return nil
+}
+
+// CreateBulkRequest is the body of POST /robots: robots are always created
+// together, one per player enrolled in a game.
+type CreateBulkRequest struct {
+	Robots []CreateRequest `json:"robots"`
+}
+
+func (req CreateBulkRequest) Validate() error {
+	return nil
+}
+
+func fromModel(r *model.Robot) Robot {
+	return Robot{
+		ID:        r.ID,
+		GameId:    r.GameId,
+		AccountId: r.AccountId,
+		Name:      r.Name,
+		CreatedAt: r.CreatedAt,
+	}
+}
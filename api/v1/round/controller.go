@@ -0,0 +1,115 @@
+package round
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alarmfox/game-repository/api"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// Controller wires the round repository to chi handlers.
+type Controller struct {
+	repo *Repository
+}
+
+// NewController returns a Controller backed by repo.
+func NewController(repo *Repository) *Controller {
+	return &Controller{repo: repo}
+}
+
+func parseID(s string) (int64, error) {
+	var k Key
+	k, err := k.Parse(s)
+	return k.AsInt64(), err
+}
+
+func (c *Controller) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	round, err := c.repo.FindByID(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&round))
+}
+
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) error {
+	rounds, err := c.repo.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]Round, 0, len(rounds))
+	for i := range rounds {
+		out = append(out, fromModel(&rounds[i]))
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) error {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	round, err := c.repo.Create(r.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(fromModel(&round))
+}
+
+func (c *Controller) Update(w http.ResponseWriter, r *http.Request) error {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	round, err := c.repo.Update(r.Context(), id, req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&round))
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := parseID(chi.URLParam(r, "id"))
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	if err := c.repo.Delete(r.Context(), id); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
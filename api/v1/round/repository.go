@@ -0,0 +1,58 @@
+package round
+
+import (
+	"context"
+
+	"github.com/alarmfox/game-repository/model"
+	"gorm.io/gorm"
+)
+
+// Repository persists rounds in Postgres.
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository returns a Repository backed by db.
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (model.Round, error) {
+	var round model.Round
+	err := r.db.WithContext(ctx).First(&round, id).Error
+	return round, err
+}
+
+func (r *Repository) List(ctx context.Context) ([]model.Round, error) {
+	var rounds []model.Round
+	err := r.db.WithContext(ctx).Find(&rounds).Error
+	return rounds, err
+}
+
+func (r *Repository) Create(ctx context.Context, req CreateRequest) (model.Round, error) {
+	round := model.Round{
+		GameId:      req.GameId,
+		TestClassId: req.TestClassId,
+		StartedAt:   req.StartedAt,
+		ClosedAt:    req.ClosedAt,
+	}
+	err := r.db.WithContext(ctx).Create(&round).Error
+	return round, err
+}
+
+func (r *Repository) Update(ctx context.Context, id int64, req UpdateRequest) (model.Round, error) {
+	round, err := r.FindByID(ctx, id)
+	if err != nil {
+		return model.Round{}, err
+	}
+
+	round.StartedAt = req.StartedAt
+	round.ClosedAt = req.ClosedAt
+
+	err = r.db.WithContext(ctx).Save(&round).Error
+	return round, err
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.Round{}, id).Error
+}
@@ -0,0 +1,192 @@
+package turn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alarmfox/game-repository/api"
+	"github.com/alarmfox/game-repository/job"
+	"github.com/alarmfox/game-repository/storage"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// Enqueuer schedules a grading job once a turn's artifact has been
+// uploaded. *job.Queue satisfies this interface.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, turnID int64, testClassId, payload string) (job.Job, error)
+}
+
+// Controller wires the turn repository to chi handlers.
+type Controller struct {
+	repo     *Repository
+	enqueuer Enqueuer
+}
+
+// NewController returns a Controller backed by repo. Every successful
+// Upload enqueues a grading job on enqueuer for the round's TestClassId.
+func NewController(repo *Repository, enqueuer Enqueuer) *Controller {
+	return &Controller{repo: repo, enqueuer: enqueuer}
+}
+
+func (c *Controller) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	turn, err := c.repo.FindByID(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&turn))
+}
+
+func (c *Controller) List(w http.ResponseWriter, r *http.Request) error {
+	turns, err := c.repo.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]Turn, 0, len(turns))
+	for i := range turns {
+		out = append(out, fromModel(&turns[i]))
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
+
+func (c *Controller) Create(w http.ResponseWriter, r *http.Request) error {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	turn, err := c.repo.Create(r.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(fromModel(&turn))
+}
+
+func (c *Controller) Update(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return api.NewBadRequestError(err)
+	}
+	if err := req.Validate(); err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	turn, err := c.repo.Update(r.Context(), id, req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(fromModel(&turn))
+}
+
+func (c *Controller) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	if err := c.repo.Delete(r.Context(), id); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// presignExpiry bounds how long a redirect issued by Download stays valid.
+const presignExpiry = 15 * time.Minute
+
+// Download streams the zip artifact uploaded for a turn, or redirects to a
+// presigned URL when the backend supports one (e.g. S3), so large artifacts
+// are served directly by the object store instead of proxied through the
+// API.
+func (c *Controller) Download(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	url, err := c.repo.PresignDownload(r.Context(), id, presignExpiry)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, storage.ErrNotExist):
+		return api.NewNotFoundError(err)
+	case errors.Is(err, storage.ErrPresignUnsupported):
+		// fall through to streaming below
+	case err != nil:
+		return err
+	default:
+		http.Redirect(w, r, url, http.StatusFound)
+		return nil
+	}
+
+	rc, metadata, err := c.repo.Open(r.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, storage.ErrNotExist) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Length", strconv.FormatInt(metadata.Size, 10))
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// Upload stores the request body as the zip artifact for a turn.
+func (c *Controller) Upload(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return api.NewBadRequestError(err)
+	}
+
+	metadata, err := c.repo.Store(r.Context(), id, r.Body)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return api.NewNotFoundError(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	testClassId, err := c.repo.RoundTestClassId(r.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.enqueuer.Enqueue(r.Context(), id, testClassId, metadata.Path); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(metadata)
+}
@@ -0,0 +1,171 @@
+package turn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/alarmfox/game-repository/model"
+	"github.com/alarmfox/game-repository/storage"
+	"gorm.io/gorm"
+)
+
+// Repository persists turns in Postgres and their uploaded artifacts in a
+// storage.Backend.
+type Repository struct {
+	db             *gorm.DB
+	backend        storage.Backend
+	uploadDeadline time.Duration
+}
+
+// Option configures a Repository.
+type Option func(*Repository)
+
+// WithUploadDeadline bounds Store's write to the storage backend to d,
+// regardless of how long the caller's own context would otherwise allow.
+// This keeps Store safe to call outside of the HTTP upload route (which
+// already applies its own request deadline) without risking an unbounded
+// write to the backend.
+func WithUploadDeadline(d time.Duration) Option {
+	return func(r *Repository) {
+		r.uploadDeadline = d
+	}
+}
+
+// NewRepository returns a Repository that stores turn rows in db and turn
+// artifacts in backend.
+func NewRepository(db *gorm.DB, backend storage.Backend, opts ...Option) *Repository {
+	r := &Repository{db: db, backend: backend}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func turnKey(id int64) string {
+	return fmt.Sprintf("turns/%d.zip", id)
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (model.Turn, error) {
+	var turn model.Turn
+	err := r.db.WithContext(ctx).Preload("Metadata").First(&turn, id).Error
+	return turn, err
+}
+
+func (r *Repository) List(ctx context.Context) ([]model.Turn, error) {
+	var turns []model.Turn
+	err := r.db.WithContext(ctx).Preload("Metadata").Find(&turns).Error
+	return turns, err
+}
+
+func (r *Repository) Create(ctx context.Context, req CreateRequest) (model.Turn, error) {
+	turn := model.Turn{RoundId: req.RoundId, RobotId: req.RobotId}
+	err := r.db.WithContext(ctx).Create(&turn).Error
+	return turn, err
+}
+
+func (r *Repository) Update(ctx context.Context, id int64, req UpdateRequest) (model.Turn, error) {
+	turn, err := r.FindByID(ctx, id)
+	if err != nil {
+		return model.Turn{}, err
+	}
+
+	turn.RoundId = req.RoundId
+	turn.RobotId = req.RobotId
+
+	err = r.db.WithContext(ctx).Save(&turn).Error
+	return turn, err
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.Turn{}, id).Error
+}
+
+// Store writes body to the backend under the key for turn id and records
+// the result as that turn's Metadata, replacing any artifact uploaded
+// previously for the same turn.
+func (r *Repository) Store(ctx context.Context, id int64, body io.Reader) (model.Metadata, error) {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return model.Metadata{}, err
+	}
+
+	if r.uploadDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.uploadDeadline)
+		defer cancel()
+	}
+
+	key := turnKey(id)
+	if err := r.backend.Put(ctx, key, body); err != nil {
+		// Put may have written a partial object before failing (e.g. the
+		// upload deadline expired mid-write); since no Metadata row is
+		// created on this path, cleanup() would never find it by scanning
+		// for orphans, so it must be removed here instead.
+		if delErr := r.backend.Delete(context.Background(), key); delErr != nil {
+			log.Printf("turn: cannot delete partial upload %q after Put error: %v", key, delErr)
+		}
+		return model.Metadata{}, err
+	}
+
+	info, err := r.backend.Stat(ctx, key)
+	if err != nil {
+		return model.Metadata{}, err
+	}
+
+	var metadata model.Metadata
+	err = r.db.WithContext(ctx).Where("turn_id = ?", id).First(&metadata).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		metadata = model.Metadata{TurnID: &id, Path: key, Size: info.Size}
+		err = r.db.WithContext(ctx).Create(&metadata).Error
+	case err == nil:
+		metadata.Size = info.Size
+		err = r.db.WithContext(ctx).Save(&metadata).Error
+	}
+
+	return metadata, err
+}
+
+// RoundTestClassId returns the TestClassId of the round turn id belongs to,
+// so the caller knows which scorer a grading job for it should run.
+func (r *Repository) RoundTestClassId(ctx context.Context, turnID int64) (string, error) {
+	var testClassId string
+	err := r.db.WithContext(ctx).
+		Table("turns").
+		Select("rounds.test_class_id").
+		Joins("JOIN rounds ON rounds.id = turns.round_id").
+		Where("turns.id = ?", turnID).
+		Scan(&testClassId).Error
+	return testClassId, err
+}
+
+// PresignDownload returns a temporary URL for turn id's artifact, valid for
+// expires, when the backend supports one. It returns
+// storage.ErrPresignUnsupported for backends (e.g. local disk) that do not.
+func (r *Repository) PresignDownload(ctx context.Context, id int64, expires time.Duration) (string, error) {
+	var metadata model.Metadata
+	if err := r.db.WithContext(ctx).Where("turn_id = ?", id).First(&metadata).Error; err != nil {
+		return "", err
+	}
+
+	return r.backend.Presign(ctx, metadata.Path, expires)
+}
+
+// Open returns the artifact stored for turn id along with its Metadata. The
+// caller must close the returned reader.
+func (r *Repository) Open(ctx context.Context, id int64) (io.ReadCloser, model.Metadata, error) {
+	var metadata model.Metadata
+	if err := r.db.WithContext(ctx).Where("turn_id = ?", id).First(&metadata).Error; err != nil {
+		return nil, model.Metadata{}, err
+	}
+
+	rc, err := r.backend.Get(ctx, metadata.Path)
+	if err != nil {
+		return nil, model.Metadata{}, err
+	}
+
+	return rc, metadata, nil
+}
@@ -0,0 +1,43 @@
+package turn
+
+import (
+	"time"
+
+	"github.com/alarmfox/game-repository/model"
+)
+
+type Turn struct {
+	ID        int64     `json:"id"`
+	RoundId   int64     `json:"roundId"`
+	RobotId   int64     `json:"robotId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CreateRequest struct {
+	RoundId int64 `json:"roundId"`
+	RobotId int64 `json:"robotId"`
+}
+
+func (CreateRequest) Validate() error {
+	return nil
+}
+
+type UpdateRequest struct {
+	RoundId int64 `json:"roundId"`
+	RobotId int64 `json:"robotId"`
+}
+
+func (UpdateRequest) Validate() error {
+	return nil
+}
+
+func fromModel(t *model.Turn) Turn {
+	return Turn{
+		ID:        t.ID,
+		RoundId:   t.RoundId,
+		RobotId:   t.RobotId,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
@@ -0,0 +1,184 @@
+// Command runner pulls queued jobs from the database the server writes to,
+// unzips the uploaded turn artifact, scores it, and reports the outcome
+// back to the server over HTTP. Several runner instances can be started
+// against the same database; the queue's row locking ensures a job is
+// leased by exactly one of them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alarmfox/game-repository/job"
+	"github.com/alarmfox/game-repository/storage"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Configuration struct {
+	PostgresUrl  string         `json:"postgresUrl"`
+	ServerUrl    string         `json:"serverUrl"`
+	RunnerToken  string         `json:"runnerToken"`
+	WorkerName   string         `json:"workerName"`
+	PollInterval time.Duration  `json:"pollInterval"`
+	Storage      storage.Config `json:"storage"`
+}
+
+func main() {
+	var (
+		configPath = flag.String("config", "runner.json", "Path for configuration")
+		ctx        = context.Background()
+	)
+	flag.Parse()
+
+	fcontent, err := os.ReadFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var configuration Configuration
+	if err := json.Unmarshal(fcontent, &configuration); err != nil {
+		log.Fatal(err)
+	}
+
+	makeDefaults(&configuration)
+
+	ctx, canc := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer canc()
+
+	if err := run(ctx, configuration); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, c Configuration) error {
+	db, err := gorm.Open(postgres.Open(c.PostgresUrl), &gorm.Config{
+		SkipDefaultTransaction: true,
+		TranslateError:         true,
+	})
+	if err != nil {
+		return err
+	}
+
+	backend, err := storage.New(c.Storage)
+	if err != nil {
+		return fmt.Errorf("cannot initialize storage backend: %w", err)
+	}
+
+	queue := job.NewQueue(db, 0)
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+
+	log.Printf("runner %q polling every %s", c.WorkerName, c.PollInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			processNext(ctx, queue, backend, httpClient, c)
+		}
+	}
+}
+
+// processNext leases a single job, if one is available, and executes it.
+// Errors are logged rather than returned so that one bad job does not stop
+// the polling loop.
+func processNext(ctx context.Context, queue *job.Queue, backend storage.Backend, httpClient *http.Client, c Configuration) {
+	j, err := queue.Lease(ctx, c.WorkerName)
+	if err != nil {
+		if err != job.ErrNoJobAvailable {
+			log.Print(err)
+		}
+		return
+	}
+
+	result, success := execute(ctx, j, backend)
+	if err := submitResult(ctx, httpClient, c, j.ID, success, result); err != nil {
+		log.Printf("job %d: cannot submit result: %v", j.ID, err)
+	}
+}
+
+// execute unzips the turn artifact and runs the scorer registered for the
+// job's TestClassId. The scorer registry is intentionally left as an
+// extension point: callers plug in real scorers per TestClassId.
+func execute(ctx context.Context, j job.Job, backend storage.Backend) (result string, success bool) {
+	artifact, err := backend.Get(ctx, j.Payload)
+	if err != nil {
+		return err.Error(), false
+	}
+	defer artifact.Close()
+
+	scorer, ok := scorers[j.TestClassId]
+	if !ok {
+		return fmt.Sprintf("no scorer registered for test class %q", j.TestClassId), false
+	}
+
+	score, err := scorer(artifact)
+	if err != nil {
+		return err.Error(), false
+	}
+
+	return score, true
+}
+
+// scorer grades the unzipped content of a turn artifact and returns a
+// result payload to store alongside the job.
+type scorer func(artifact io.Reader) (string, error)
+
+// scorers maps a round's TestClassId to the scorer that grades it.
+var scorers = map[string]scorer{}
+
+type resultRequest struct {
+	Success bool   `json:"success"`
+	Result  string `json:"result"`
+}
+
+func submitResult(ctx context.Context, httpClient *http.Client, c Configuration, jobID int64, success bool, result string) error {
+	body, err := json.Marshal(resultRequest{Success: success, Result: result})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/jobs/%d/result", c.ServerUrl, jobID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Runner-Token", c.RunnerToken)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func makeDefaults(c *Configuration) {
+	if c.PollInterval == 0 {
+		c.PollInterval = 5 * time.Second
+	}
+
+	if c.WorkerName == "" {
+		hostname, _ := os.Hostname()
+		c.WorkerName = hostname
+	}
+}
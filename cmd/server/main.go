@@ -0,0 +1,583 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alarmfox/game-repository/api"
+	"github.com/alarmfox/game-repository/api/auth"
+	apijob "github.com/alarmfox/game-repository/api/job"
+	"github.com/alarmfox/game-repository/api/v1/game"
+	"github.com/alarmfox/game-repository/api/v1/robot"
+	"github.com/alarmfox/game-repository/api/v1/round"
+	"github.com/alarmfox/game-repository/api/v1/turn"
+	"github.com/alarmfox/game-repository/job"
+	"github.com/alarmfox/game-repository/model"
+	"github.com/alarmfox/game-repository/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Configuration struct {
+	PostgresUrl           string         `json:"postgresUrl"`
+	ListenAddress         string         `json:"listenAddress"`
+	ApiPrefix             string         `json:"apiPrefix"`
+	DataDir               string         `json:"dataDir"`
+	CleanupInterval       time.Duration  `json:"cleanupInterval"`
+	Storage               storage.Config `json:"storage"`
+	RunnerToken           string         `json:"runnerToken"`
+	JobLeaseDuration      time.Duration  `json:"jobLeaseDuration"`
+	Auth                  auth.Config    `json:"auth"`
+	RequestDeadline       time.Duration  `json:"requestDeadline"`
+	UploadDeadline        time.Duration  `json:"uploadDeadline"`
+	DeprecatedAliasSunset time.Time      `json:"deprecatedAliasSunset"`
+	ShutdownGracePeriod   time.Duration  `json:"shutdownGracePeriod"`
+}
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.json", "Path for configuration")
+		ctx        = context.Background()
+	)
+	flag.Parse()
+	rand.Seed(time.Now().Unix())
+
+	fcontent, err := os.ReadFile(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var configuration Configuration
+	if err := json.Unmarshal(fcontent, &configuration); err != nil {
+		log.Fatal(err)
+	}
+
+	makeDefaults(&configuration)
+
+	ctx, canc := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer canc()
+
+	if err := run(ctx, configuration); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, c Configuration) error {
+
+	db, err := gorm.Open(postgres.Open(c.PostgresUrl), &gorm.Config{
+		SkipDefaultTransaction: true,
+		TranslateError:         true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(
+		&model.Game{},
+		&model.Round{},
+		&model.Player{},
+		&model.Turn{},
+		&model.Metadata{},
+		&model.PlayerGame{},
+		&model.Robot{},
+		&job.Job{})
+
+	if err != nil {
+		return err
+	}
+
+	backend, err := storage.New(c.Storage)
+	if err != nil {
+		return fmt.Errorf("cannot initialize storage backend: %w", err)
+	}
+
+	queue := job.NewQueue(db, c.JobLeaseDuration)
+
+	validator, err := auth.NewJWTValidator(c.Auth)
+	if err != nil {
+		return fmt.Errorf("cannot initialize auth validator: %w", err)
+	}
+
+	var (
+		ready    atomic.Bool
+		inFlight sync.WaitGroup
+	)
+
+	r := chi.NewRouter()
+
+	// basic cors: chi panics if Use is called after a route has already
+	// been registered on this mux, so every middleware must come before
+	// the probe routes below.
+	r.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Accept", "Authorization"},
+		ExposedHeaders:   []string{"Link"},
+		AllowCredentials: false,
+		MaxAge:           300, // Maximum value not ignored by any of major browsers
+	}))
+
+	// liveness/readiness probes for the load balancer: readyz flips as soon
+	// as shutdown starts so the balancer stops routing before Shutdown is
+	// even called
+	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Get("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// metrics endpoint, including queue depth / worker count next to the
+	// default process metrics
+	registerQueueMetrics(db)
+	r.Handle("/metrics", promhttp.Handler())
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Logger)
+		r.Use(middleware.Recoverer)
+
+		var (
+
+			// game endpoint
+			gameController = game.NewController(game.NewRepository(db))
+
+			// round endpoint
+			roundController = round.NewController(round.NewRepository(db))
+
+			// turn endpoint: every successful upload enqueues a grading job
+			turnController = turn.NewController(turn.NewRepository(db, backend, turn.WithUploadDeadline(c.UploadDeadline)), queue)
+
+			// robot endpoint
+			robotController = robot.NewController(robot.NewRobotStorage(db))
+
+			// job endpoint
+			jobController = apijob.NewController(queue, c.RunnerToken)
+
+			// auth endpoint
+			authController = auth.NewController(c.Auth.SharedSecret, 0, c.Auth.ClockSkew)
+		)
+
+		apiRoutes := setupRoutes(
+			gameController,
+			roundController,
+			turnController,
+			robotController,
+			jobController,
+			authController,
+			validator,
+			c.RequestDeadline,
+			c.UploadDeadline,
+			&inFlight,
+		)
+
+		// canonical, versioned surface
+		r.Mount(path.Join(c.ApiPrefix, "v1"), apiRoutes)
+
+		// unversioned paths are kept as deprecated aliases of /v1 so
+		// existing clients keep working while they migrate
+		r.With(deprecated(c.DeprecatedAliasSunset)).Mount(c.ApiPrefix, apiRoutes)
+	})
+	ready.Store(true)
+
+	log.Printf("listening on %s", c.ListenAddress)
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return startHttpServer(ctx, r, c.ListenAddress, c.ShutdownGracePeriod)
+	})
+
+	g.Go(func() error {
+		ticker := time.NewTicker(c.CleanupInterval)
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := cleanup(ctx, db, backend); err != nil {
+					log.Print(err)
+				}
+				if _, err := queue.ReapExpired(ctx); err != nil {
+					log.Print(err)
+				}
+			case <-ctx.Done():
+				// flush one final pass so orphan metadata/jobs are not
+				// left behind by whatever period the ticker was mid-way
+				// through when shutdown started
+				flushCtx, canc := context.WithTimeout(context.Background(), c.ShutdownGracePeriod)
+				defer canc()
+				if _, err := cleanup(flushCtx, db, backend); err != nil {
+					log.Print(err)
+				}
+				if _, err := queue.ReapExpired(flushCtx); err != nil {
+					log.Print(err)
+				}
+				return nil
+			}
+		}
+	})
+
+	g.Go(func() error {
+		<-ctx.Done()
+		ready.Store(false)
+
+		done := make(chan struct{})
+		go func() {
+			inFlight.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(c.ShutdownGracePeriod):
+			log.Print("shutdown grace period elapsed with turn uploads still in flight")
+		}
+		return nil
+	})
+
+	return g.Wait()
+
+}
+
+func startHttpServer(ctx context.Context, r chi.Router, addr string, shutdownGracePeriod time.Duration) error {
+	// ReadTimeout/WriteTimeout used to bound the whole request here; that is
+	// now handled per route group by withDeadline, which cancels r.Context()
+	// (and, through it, any in-flight DB query or storage call) instead of
+	// just aborting the HTTP connection.
+	server := http.Server{
+		Addr:              addr,
+		Handler:           r,
+		IdleTimeout:       time.Minute,
+		ReadHeaderTimeout: 10 * time.Second,
+		MaxHeaderBytes:    1024 * 8,
+	}
+
+	errCh := make(chan error)
+	defer close(errCh)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		return err
+	}
+
+	ctx, canc := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer canc()
+
+	return server.Shutdown(ctx)
+}
+
+func cleanup(ctx context.Context, db *gorm.DB, backend storage.Backend) (int64, error) {
+	var (
+		metadata []model.Metadata
+		err      error
+		n        int64
+	)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("turn_id IS NULL").
+			Find(&metadata).
+			Count(&n).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		var deleted []int64
+		for _, m := range metadata {
+			if err := backend.Delete(ctx, m.Path); err != nil && !errors.Is(err, storage.ErrNotExist) {
+				log.Print(err)
+			} else {
+				deleted = append(deleted, m.ID)
+			}
+		}
+
+		return tx.Delete(&[]model.Metadata{}, deleted).Error
+	})
+
+	return n, err
+}
+
+// registerQueueMetrics exposes job queue depth and worker activity next to
+// the process metrics already served on /metrics.
+func registerQueueMetrics(db *gorm.DB) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "game_repository_jobs_queued",
+			Help: "Number of jobs waiting to be leased by a runner.",
+		},
+		func() float64 {
+			var n int64
+			db.Model(&job.Job{}).Where("state = ?", job.StateQueued).Count(&n)
+			return float64(n)
+		},
+	))
+
+	prometheus.MustRegister(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "game_repository_jobs_running",
+			Help: "Number of jobs currently leased by a runner.",
+		},
+		func() float64 {
+			var n int64
+			db.Model(&job.Job{}).Where("state = ?", job.StateRunning).Count(&n)
+			return float64(n)
+		},
+	))
+}
+
+func makeDefaults(c *Configuration) {
+	if c.ApiPrefix == "" {
+		c.ApiPrefix = "/"
+	}
+
+	if c.ListenAddress == "" {
+		c.ListenAddress = "localhost:3000"
+	}
+
+	if c.DataDir == "" {
+		c.DataDir = "data"
+	}
+
+	// Keep `dataDir` working for existing deployments that have not moved
+	// to the new `storage` block yet.
+	if c.Storage.Driver == "" && c.Storage.Directory == "" {
+		c.Storage.Directory = c.DataDir
+	}
+
+	if int64(c.CleanupInterval) == 0 {
+		c.CleanupInterval = time.Hour
+	}
+
+	if c.JobLeaseDuration == 0 {
+		c.JobLeaseDuration = 5 * time.Minute
+	}
+
+	if c.RequestDeadline == 0 {
+		c.RequestDeadline = 5 * time.Second
+	}
+
+	if c.UploadDeadline == 0 {
+		c.UploadDeadline = 60 * time.Second
+	}
+
+	if c.DeprecatedAliasSunset.IsZero() {
+		c.DeprecatedAliasSunset = time.Now().AddDate(0, 6, 0)
+	}
+
+	if c.ShutdownGracePeriod == 0 {
+		c.ShutdownGracePeriod = 30 * time.Second
+	}
+
+}
+
+// deprecated marks every response on the wrapped handler as deprecated per
+// RFC 8594, pointing clients at the versioned /v1 surface they should move
+// to before sunset.
+func deprecated(sunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// trackInFlight registers the request with wg for the duration of the
+// handler, letting shutdown wait for active turn uploads to finish instead
+// of cutting them off mid-write.
+func trackInFlight(wg *sync.WaitGroup) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wg.Add(1)
+			defer wg.Done()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withDeadline bounds r.Context() to d for the lifetime of the request, so
+// that a client disconnect or a slow downstream call (a Postgres query, a
+// storage backend read) is cancelled instead of running to completion
+// after nobody is listening for the response anymore.
+func withDeadline(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func setupRoutes(gc *game.Controller, rc *round.Controller, tc *turn.Controller, roc *robot.Controller, jc *apijob.Controller, ac *auth.Controller, validator auth.TokenValidator, requestDeadline, uploadDeadline time.Duration, inFlight *sync.WaitGroup) *chi.Mux {
+	r := chi.NewRouter()
+
+	r.Use(api.WithMaximumBodySize(api.DefaultBodySize))
+
+	r.Group(func(r chi.Router) {
+		// authenticate every route in this group; individual routes then
+		// layer on RequireRole/RequireSelfOrRole for authorization. The
+		// job result callback and the token refresh endpoint are
+		// deliberately mounted outside this group: the former uses its
+		// own shared-token scheme, the latter must accept a token that
+		// may already be expired.
+		r.Use(auth.Middleware(validator))
+
+		r.Route("/games", func(r chi.Router) {
+			r.Use(withDeadline(requestDeadline))
+
+			//Get game
+			r.Get("/{id}", api.HandlerFunc(gc.FindByID))
+
+			// List games
+			r.Get("/", api.HandlerFunc(gc.List))
+
+			// Get game by player: a player may only read their own games
+			r.With(auth.RequireSelfOrRole("accountId", "admin")).
+				Get("/byplayer/{accountId}", api.HandlerFunc(gc.FindByPlayer))
+
+			// Create game
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin")).
+				Post("/", api.HandlerFunc(gc.Create))
+
+			// Update game
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin")).
+				Put("/{id}", api.HandlerFunc(gc.Update))
+
+			// Delete game
+			r.With(auth.RequireRole("admin")).
+				Delete("/{id}", api.HandlerFunc(gc.Delete))
+
+		})
+
+		r.Route("/rounds", func(r chi.Router) {
+			r.Use(withDeadline(requestDeadline))
+
+			// Get round
+			r.Get("/{id}", api.HandlerFunc(rc.FindByID))
+
+			// List rounds
+			r.Get("/", api.HandlerFunc(rc.List))
+
+			// Create round
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin")).
+				Post("/", api.HandlerFunc(rc.Create))
+
+			// Update round
+			r.With(auth.RequireRole("admin")).
+				Put("/{id}", api.HandlerFunc(rc.Update))
+
+			// Delete round
+			r.With(auth.RequireRole("admin")).
+				Delete("/{id}", api.HandlerFunc(rc.Delete))
+
+		})
+
+		r.Route("/turns", func(r chi.Router) {
+			// Get turn
+			r.With(withDeadline(requestDeadline)).
+				Get("/{id}", api.HandlerFunc(tc.FindByID))
+
+			// List turn
+			r.With(withDeadline(requestDeadline)).
+				Get("/", api.HandlerFunc(tc.List))
+
+			// Create turn
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin", "player"), withDeadline(requestDeadline)).
+				Post("/", api.HandlerFunc(tc.Create))
+
+			// Update turn
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin", "player"), withDeadline(requestDeadline)).
+				Put("/{id}", api.HandlerFunc(tc.Update))
+
+			// Delete turn
+			r.With(auth.RequireRole("admin"), withDeadline(requestDeadline)).
+				Delete("/{id}", api.HandlerFunc(tc.Delete))
+
+			// Get turn file; uses uploadDeadline too since streaming a large
+			// artifact out of the storage backend can take as long as
+			// writing it did
+			r.With(withDeadline(uploadDeadline)).
+				Get("/{id}/files", api.HandlerFunc(tc.Download))
+
+			// Upload turn file: bounds the multipart read, the disk/backend
+			// write and the metadata insert to a single absolute deadline,
+			// and is tracked in inFlight so shutdown can drain it first
+			r.With(middleware.AllowContentType("application/zip"),
+				api.WithMaximumBodySize(api.MaxUploadSize),
+				auth.RequireRole("admin", "player"),
+				withDeadline(uploadDeadline),
+				trackInFlight(inFlight)).
+				Put("/{id}/files", api.HandlerFunc(tc.Upload))
+		})
+
+		r.Route("/robots", func(r chi.Router) {
+			r.Use(withDeadline(requestDeadline))
+
+			// Get robot with filter
+			r.Get("/", api.HandlerFunc(roc.FindByFilter))
+
+			// Create robots in bulk
+			r.With(middleware.AllowContentType("application/json"), auth.RequireRole("admin")).
+				Post("/", api.HandlerFunc(roc.CreateBulk))
+
+			r.With(auth.RequireRole("admin")).
+				Delete("/", api.HandlerFunc(roc.Delete))
+
+		})
+
+		r.Route("/jobs", func(r chi.Router) {
+			r.Use(withDeadline(requestDeadline))
+
+			// Poll job status: requires the same bearer auth as every
+			// other resource, so a job's grading result cannot be read by
+			// an unauthenticated caller who merely guesses its id
+			r.Get("/{id}", api.HandlerFunc(jc.FindByID))
+		})
+	})
+
+	// Runner result callback is mounted outside the auth group: it is
+	// authenticated by its own shared runner token, not the player/admin
+	// JWT scheme.
+	r.Route("/jobs", func(r chi.Router) {
+		r.With(middleware.AllowContentType("application/json")).
+			Post("/{id}/result", api.HandlerFunc(jc.SubmitResult))
+	})
+
+	r.Route("/auth", func(r chi.Router) {
+		r.With(middleware.AllowContentType("application/json")).
+			Post("/refresh", api.HandlerFunc(ac.Refresh))
+	})
+
+	return r
+}
@@ -0,0 +1,33 @@
+// Package job implements the post-processing queue: the server enqueues a
+// Job whenever a turn upload needs grading, and one or more runner
+// processes lease and execute queued jobs.
+package job
+
+import "time"
+
+// State is the lifecycle state of a Job.
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Job is a unit of grading work for a turn upload: unzip the artifact and
+// run the scorer registered for TestClassId.
+type Job struct {
+	ID             int64      `json:"id" gorm:"primaryKey"`
+	TurnID         int64      `json:"turnId"`
+	TestClassId    string     `json:"testClassId"`
+	State          State      `json:"state" gorm:"default:queued"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"maxAttempts"`
+	Payload        string     `json:"payload"`
+	Result         string     `json:"result,omitempty"`
+	LeasedBy       string     `json:"leasedBy,omitempty"`
+	LeaseExpiresAt *time.Time `json:"leaseExpiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
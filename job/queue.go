@@ -0,0 +1,123 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoJobAvailable is returned by Lease when there is no queued job for a
+// runner to pick up.
+var ErrNoJobAvailable = errors.New("job: no job available")
+
+// Queue is a Postgres-backed job queue built directly on the application
+// database, so no extra broker needs to be deployed alongside the server
+// and runner binaries.
+type Queue struct {
+	db            *gorm.DB
+	leaseDuration time.Duration
+}
+
+// NewQueue returns a Queue. leaseDuration bounds how long a runner may hold
+// a job before ReapExpired puts it back on the queue; it defaults to five
+// minutes.
+func NewQueue(db *gorm.DB, leaseDuration time.Duration) *Queue {
+	if leaseDuration == 0 {
+		leaseDuration = 5 * time.Minute
+	}
+	return &Queue{db: db, leaseDuration: leaseDuration}
+}
+
+// Enqueue creates a queued Job for turnID and returns it.
+func (q *Queue) Enqueue(ctx context.Context, turnID int64, testClassId, payload string) (Job, error) {
+	j := Job{
+		TurnID:      turnID,
+		TestClassId: testClassId,
+		State:       StateQueued,
+		MaxAttempts: 3,
+		Payload:     payload,
+	}
+	err := q.db.WithContext(ctx).Create(&j).Error
+	return j, err
+}
+
+// Get returns the job identified by id.
+func (q *Queue) Get(ctx context.Context, id int64) (Job, error) {
+	var j Job
+	err := q.db.WithContext(ctx).First(&j, id).Error
+	return j, err
+}
+
+// Lease atomically picks the oldest available job (queued, or running with
+// an expired lease), marks it running under worker and returns it. Several
+// runner processes can call Lease concurrently against the same table: the
+// row lock with SKIP LOCKED guarantees a job is handed to exactly one of
+// them.
+func (q *Queue) Lease(ctx context.Context, worker string) (Job, error) {
+	var j Job
+	err := q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("state = ? OR (state = ? AND lease_expires_at < ?)", StateQueued, StateRunning, now).
+			Order("created_at").
+			First(&j).Error
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNoJobAvailable
+		}
+		if err != nil {
+			return err
+		}
+
+		expiresAt := now.Add(q.leaseDuration)
+		j.State = StateRunning
+		j.Attempts++
+		j.LeasedBy = worker
+		j.LeaseExpiresAt = &expiresAt
+
+		return tx.Save(&j).Error
+	})
+
+	return j, err
+}
+
+// Complete marks job as succeeded and stores its result payload.
+func (q *Queue) Complete(ctx context.Context, id int64, result string) error {
+	return q.db.WithContext(ctx).Model(&Job{}).Where("id = ?", id).
+		Updates(map[string]any{"state": StateSucceeded, "result": result}).Error
+}
+
+// Fail marks job as failed, or re-queues it when it still has attempts
+// left.
+func (q *Queue) Fail(ctx context.Context, id int64, reason string) error {
+	j, err := q.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	state := StateFailed
+	if j.Attempts < j.MaxAttempts {
+		state = StateQueued
+	}
+
+	return q.db.WithContext(ctx).Model(&j).Updates(map[string]any{
+		"state":  state,
+		"result": reason,
+	}).Error
+}
+
+// ReapExpired re-queues running jobs whose lease expired without a result,
+// so a runner that crashed mid-job does not strand it forever. It is meant
+// to be called from the same ticker that already drives orphan metadata
+// cleanup.
+func (q *Queue) ReapExpired(ctx context.Context) (int64, error) {
+	res := q.db.WithContext(ctx).Model(&Job{}).
+		Where("state = ? AND lease_expires_at < ?", StateRunning, time.Now()).
+		Updates(map[string]any{"state": StateQueued, "lease_expires_at": nil})
+
+	return res.RowsAffected, res.Error
+}
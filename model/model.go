@@ -0,0 +1,76 @@
+// Package model holds the gorm entities shared by every repository in the
+// application. Keeping them in one place avoids import cycles between, for
+// example, the round and turn packages.
+package model
+
+import "time"
+
+// Player is an account that can be enrolled in one or more games.
+type Player struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	AccountId string `json:"accountId" gorm:"uniqueIndex"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Game groups a series of rounds played by a set of players.
+type Game struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Rounds    []Round      `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+	Players   []PlayerGame `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// PlayerGame is the join table enrolling a Player in a Game.
+type PlayerGame struct {
+	PlayerID int64 `gorm:"primaryKey"`
+	GameID   int64 `gorm:"primaryKey"`
+}
+
+// Round is one graded stage of a Game: players submit turns against
+// TestClassId until ClosedAt.
+type Round struct {
+	ID          int64  `json:"id" gorm:"primaryKey"`
+	GameId      int64  `json:"gameId"`
+	Order       int    `json:"order"`
+	TestClassId string `json:"testClassId"`
+	StartedAt   time.Time
+	ClosedAt    time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Turns       []Turn `json:"-" gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+// Robot is a player-controlled entity participating in a Game.
+type Robot struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	GameId    int64  `json:"gameId"`
+	AccountId string `json:"accountId"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+}
+
+// Turn is one submission by a Robot within a Round. The graded artifact
+// itself is stored out-of-band in a storage.Backend; Metadata records where.
+type Turn struct {
+	ID        int64 `json:"id" gorm:"primaryKey"`
+	RoundId   int64 `json:"roundId"`
+	RobotId   int64 `json:"robotId"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Metadata  *Metadata `json:"metadata,omitempty"`
+}
+
+// Metadata describes a turn artifact stored in a storage.Backend. TurnID is
+// nullable and set to NULL (ON DELETE SET NULL) when the owning turn is
+// deleted; periodic cleanup deletes every Metadata row whose TurnID is NULL
+// along with the backend object at Path.
+type Metadata struct {
+	ID        int64  `json:"id" gorm:"primaryKey"`
+	TurnID    *int64 `json:"turnId" gorm:"index;constraint:OnDelete:SET NULL;"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	CreatedAt time.Time
+}
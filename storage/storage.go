@@ -0,0 +1,82 @@
+// Package storage abstracts where turn artifacts are persisted so that
+// callers (the turn repository, the orphan cleanup routine) do not need to
+// know whether a blob lives on local disk or in an object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotExist is returned by Get, Delete and Stat when the key does not
+// exist in the backend. Callers that previously relied on
+// errors.Is(err, os.ErrNotExist) should switch to errors.Is(err,
+// storage.ErrNotExist).
+var ErrNotExist = errors.New("storage: key does not exist")
+
+// ErrPresignUnsupported is returned by Presign when the backend has no
+// notion of temporary URLs (e.g. the local driver).
+var ErrPresignUnsupported = errors.New("storage: backend does not support presigned URLs")
+
+// Info describes a stored object.
+type Info struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Backend is implemented by every storage driver. Keys are backend-relative
+// paths (normally the turn id or a derived file name) and must not contain
+// a leading slash.
+type Backend interface {
+	// Put stores the content of r under key, overwriting any existing
+	// object.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the object stored under key. The caller must close the
+	// returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key. It returns nil if the
+	// key does not exist.
+	Delete(ctx context.Context, key string) error
+
+	// Stat returns metadata about the object stored under key.
+	Stat(ctx context.Context, key string) (Info, error)
+
+	// Presign returns a temporary URL that lets a client fetch the object
+	// directly from the backend without going through the API. It returns
+	// ErrPresignUnsupported when the backend has no such capability.
+	Presign(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Config selects and configures a storage backend. It is embedded in the
+// application Configuration as the `storage` block.
+type Config struct {
+	Driver    string `json:"driver"`
+	Directory string `json:"directory"`
+	Bucket    string `json:"bucket"`
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Prefix    string `json:"prefix"`
+}
+
+// New builds the Backend selected by c.Driver. An empty driver defaults to
+// "local" so existing configurations keep working unchanged.
+func New(c Config) (Backend, error) {
+	switch c.Driver {
+	case "", "local":
+		dir := c.Directory
+		if dir == "" {
+			dir = "data"
+		}
+		return NewLocal(dir)
+	case "s3":
+		return NewS3(c)
+	default:
+		return nil, errors.New("storage: unknown driver " + c.Driver)
+	}
+}